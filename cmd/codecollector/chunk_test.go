@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Anomalybound/codecollector/pkg/collector"
+)
+
+func TestExportMarkdownChunkedSplitsOversizedFile(t *testing.T) {
+	data := collector.CollectedData{
+		Tree: "big.go\n",
+		Files: []collector.FileData{
+			{RelativePath: "big.go", Content: strings.Repeat("line\n", 200)},
+		},
+	}
+
+	outputFile := filepath.Join(t.TempDir(), "out")
+	if err := exportMarkdownChunked(data, outputFile, 20); err != nil {
+		t.Fatalf("exportMarkdownChunked: %v", err)
+	}
+
+	manifestBytes, err := os.ReadFile(outputFile + ".manifest.json")
+	if err != nil {
+		t.Fatalf("reading manifest: %v", err)
+	}
+	var manifest chunkManifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		t.Fatalf("unmarshalling manifest: %v", err)
+	}
+
+	if manifest.Parts < 2 {
+		t.Fatalf("expected big.go to be split across multiple parts, got %d part(s)", manifest.Parts)
+	}
+	if len(manifest.Files) < 2 {
+		t.Fatalf("expected multiple manifest entries for the split file, got %+v", manifest.Files)
+	}
+	for _, entry := range manifest.Files {
+		if entry.File != "big.go" {
+			t.Fatalf("unexpected manifest entry %+v", entry)
+		}
+	}
+	if !manifest.Files[0].Split {
+		t.Fatalf("manifest entries for a split file should have Split=true, got %+v", manifest.Files[0])
+	}
+
+	if _, err := os.Stat(outputFile + ".part1.md"); err != nil {
+		t.Fatalf("expected part1 file to exist: %v", err)
+	}
+}
+
+func TestExportMarkdownChunkedKeepsSmallFilesWhole(t *testing.T) {
+	data := collector.CollectedData{
+		Tree: "a.go\nb.go\n",
+		Files: []collector.FileData{
+			{RelativePath: "a.go", Content: "package a"},
+			{RelativePath: "b.go", Content: "package b"},
+		},
+	}
+
+	outputFile := filepath.Join(t.TempDir(), "out")
+	if err := exportMarkdownChunked(data, outputFile, 0); err != nil {
+		t.Fatalf("exportMarkdownChunked: %v", err)
+	}
+
+	content, err := os.ReadFile(outputFile + ".part1.md")
+	if err != nil {
+		t.Fatalf("reading part1: %v", err)
+	}
+	if !strings.Contains(string(content), "a.go") || !strings.Contains(string(content), "b.go") {
+		t.Fatalf("expected both files in the single unbounded part, got:\n%s", content)
+	}
+}
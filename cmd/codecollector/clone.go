@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+// cloneOptions configures cloneRepo beyond the bare URL.
+type cloneOptions struct {
+	// Ref is a branch, tag, or commit SHA to check out. Empty means the
+	// remote's default branch.
+	Ref string
+	// Subpath restricts the returned root to a subtree of the clone.
+	Subpath string
+	// SSHKey is a path to a private key file, for ssh:// URLs.
+	SSHKey string
+	// Token is a bearer/basic credential for http(s):// URLs.
+	Token string
+	// TokenEnv names an environment variable to read Token from when Token
+	// itself is empty.
+	TokenEnv string
+}
+
+// cloneRepo clones url into a temp directory and returns (rootDir, cloneDir,
+// err): rootDir is the directory to walk (the clone, or opts.Subpath within
+// it), and cloneDir is the temp directory the caller must os.RemoveAll once
+// done, regardless of opts.Subpath. url may be any go-git-supported
+// transport: GitHub, GitLab, Gitea, Bitbucket, plain ssh://, file://, or a
+// path to a local (possibly bare) repo -- go-git picks the transport from
+// the URL scheme, so no special casing is needed here. When opts.Ref is
+// empty, the clone is shallow (Depth: 1, single branch) exactly as before; a
+// non-empty Ref requires the full history to resolve a branch, tag, or
+// commit SHA via ResolveRevision.
+func cloneRepo(url string, opts cloneOptions) (rootDir, cloneDir string, err error) {
+	cloneDir, err = os.MkdirTemp("", "repo-")
+	if err != nil {
+		return "", "", err
+	}
+
+	auth, err := resolveAuth(opts)
+	if err != nil {
+		os.RemoveAll(cloneDir)
+		return "", "", err
+	}
+
+	cloneOpts := &git.CloneOptions{URL: url, Auth: auth}
+	if opts.Ref == "" {
+		cloneOpts.Depth = 1
+		cloneOpts.SingleBranch = true
+	}
+
+	repo, err := git.PlainClone(cloneDir, false, cloneOpts)
+	if err != nil {
+		os.RemoveAll(cloneDir)
+		return "", "", fmt.Errorf("cloning %s: %w", url, err)
+	}
+
+	if opts.Ref != "" {
+		if err := checkoutRef(repo, opts.Ref); err != nil {
+			os.RemoveAll(cloneDir)
+			return "", "", err
+		}
+	}
+
+	rootDir = cloneDir
+	if opts.Subpath != "" {
+		rootDir, err = resolveSubpath(cloneDir, opts.Subpath)
+		if err != nil {
+			os.RemoveAll(cloneDir)
+			return "", "", err
+		}
+		if info, err := os.Stat(rootDir); err != nil || !info.IsDir() {
+			os.RemoveAll(cloneDir)
+			return "", "", fmt.Errorf("subpath %q not found in %s", opts.Subpath, url)
+		}
+	}
+
+	return rootDir, cloneDir, nil
+}
+
+// resolveSubpath joins subpath onto cloneDir and rejects anything that
+// escapes it -- a "../../etc" or an absolute subpath would otherwise let the
+// collector walk and export arbitrary host paths outside the temp clone.
+func resolveSubpath(cloneDir, subpath string) (string, error) {
+	clean := filepath.FromSlash(subpath)
+	if filepath.IsAbs(clean) {
+		return "", fmt.Errorf("subpath %q must be relative to the repo root", subpath)
+	}
+
+	joined := filepath.Join(cloneDir, clean)
+
+	rel, err := filepath.Rel(cloneDir, joined)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("subpath %q escapes the clone directory", subpath)
+	}
+	return joined, nil
+}
+
+// checkoutRef resolves ref as a branch, tag, or commit SHA and checks it out
+// in repo's worktree.
+func checkoutRef(repo *git.Repository, ref string) error {
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return fmt.Errorf("resolving ref %q: %w", ref, err)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	return wt.Checkout(&git.CheckoutOptions{Hash: *hash})
+}
+
+// resolveAuth builds the transport.AuthMethod cloneRepo should use, if any.
+// An explicit SSH key wins, then an explicit token, then a token read from
+// TokenEnv.
+func resolveAuth(opts cloneOptions) (transport.AuthMethod, error) {
+	switch {
+	case opts.SSHKey != "":
+		auth, err := ssh.NewPublicKeysFromFile("git", opts.SSHKey, "")
+		if err != nil {
+			return nil, fmt.Errorf("loading ssh key %s: %w", opts.SSHKey, err)
+		}
+		return auth, nil
+	case opts.Token != "":
+		return &http.BasicAuth{Username: "x-access-token", Password: opts.Token}, nil
+	case opts.TokenEnv != "":
+		if token := os.Getenv(opts.TokenEnv); token != "" {
+			return &http.BasicAuth{Username: "x-access-token", Password: token}, nil
+		}
+	}
+	return nil, nil
+}
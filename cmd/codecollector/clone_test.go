@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+func TestResolveSubpathRejectsTraversal(t *testing.T) {
+	cloneDir := t.TempDir()
+
+	cases := []struct {
+		name    string
+		subpath string
+		wantErr bool
+	}{
+		{"plain subdir", "src/pkg", false},
+		{"dot-relative", "./src", false},
+		{"parent traversal", "../../etc", true},
+		{"parent traversal suffix", "src/../../etc", true},
+		{"bare parent", "..", true},
+		{"absolute path", "/etc/passwd", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := resolveSubpath(cloneDir, tc.subpath)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("resolveSubpath(%q) = %q, nil; want an error", tc.subpath, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveSubpath(%q): %v", tc.subpath, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Anomalybound/codecollector/pkg/collector"
+	"github.com/go-enry/go-enry/v2"
+)
+
+func exportOutput(data collector.CollectedData, outputFile, format string) error {
+	switch format {
+	case "json":
+		return exportJSON(data, outputFile+".json")
+	case "text":
+		return exportText(data, outputFile+".txt")
+	case "markdown":
+		return exportMarkdown(data, outputFile+".md")
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+func exportJSON(data collector.CollectedData, outputFile string) error {
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+func exportText(data collector.CollectedData, outputFile string) error {
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	defer file.Close()
+
+	file.WriteString("Code Collection Report\n\n")
+	file.WriteString("Directory Structure:\n\n")
+	file.WriteString(data.Tree)
+	file.WriteString("\n")
+
+	for _, fileData := range data.Files {
+		file.WriteString(fmt.Sprintf("File: %s\n\n", fileData.RelativePath))
+		file.WriteString("Content:\n\n")
+		file.WriteString(fileData.Content)
+		file.WriteString("\n\n")
+		file.WriteString(strings.Repeat("-", 80) + "\n\n")
+	}
+
+	return nil
+}
+
+// treeRecord is the first line of a jsonl export, so a consumer streaming the
+// file can render the directory structure before any file records arrive.
+type treeRecord struct {
+	Type string `json:"type"`
+	Tree string `json:"tree"`
+}
+
+// fileRecord is one line per collected file in a jsonl export.
+type fileRecord struct {
+	Type string `json:"type"`
+	collector.FileData
+}
+
+// exportJSONL writes one JSON object per line as files are produced by
+// c.Walk, rather than buffering the whole CollectedData first. This keeps
+// memory flat on large repos and lets the output be piped straight into jq
+// or a log shipper.
+func exportJSONL(ctx context.Context, c *collector.Collector, outputFile string) error {
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	defer file.Close()
+
+	tree, err := c.Tree(ctx)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(file)
+	if err := encoder.Encode(treeRecord{Type: "tree", Tree: tree}); err != nil {
+		return err
+	}
+
+	files, err := c.Walk(ctx)
+	if err != nil {
+		return err
+	}
+	for fileData := range files {
+		if err := encoder.Encode(fileRecord{Type: "file", FileData: fileData}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func exportMarkdown(data collector.CollectedData, outputFile string) error {
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	defer file.Close()
+
+	// Write title
+	file.WriteString("# Code Collection Report\n\n")
+
+	// Write directory structure
+	file.WriteString("## Directory Structure\n\n")
+	file.WriteString("```plaintext\n")
+	file.WriteString(data.Tree)
+	file.WriteString("```\n\n")
+
+	// Write file contents
+	file.WriteString("## File Contents\n\n")
+	for _, fileData := range data.Files {
+		file.WriteString(fmt.Sprintf("### %s\n\n", fileData.RelativePath))
+		file.WriteString(fmt.Sprintf("```%s\n", detectLanguage(fileData)))
+		file.WriteString(fileData.Content)
+		file.WriteString("\n```\n\n")
+	}
+
+	return nil
+}
+
+// detectLanguage resolves the fenced-code-block language for a file, falling
+// back from extension to content sniffing to a plain "plaintext" tag.
+func detectLanguage(fileData collector.FileData) string {
+	language, _ := enry.GetLanguageByExtension(fileData.RelativePath)
+	if language == "" {
+		language = enry.GetLanguage(fileData.RelativePath, []byte(fileData.Content))
+	}
+	if language == "" {
+		language = "plaintext"
+	}
+	return language
+}
+
+// approxBytesPerToken is the rough heuristic used to estimate token counts
+// without pulling in a tokenizer: len(content)/4.
+const approxBytesPerToken = 4
+
+func estimateTokens(s string) int {
+	tokens := len(s) / approxBytesPerToken
+	if tokens == 0 && len(s) > 0 {
+		tokens = 1
+	}
+	return tokens
+}
+
+// chunkManifestEntry records which part a file (or a split fragment of one)
+// landed in.
+type chunkManifestEntry struct {
+	File  string `json:"file"`
+	Part  int    `json:"part"`
+	Split bool   `json:"split,omitempty"`
+}
+
+// chunkManifest is written alongside a chunked export so callers know which
+// part to open for a given file.
+type chunkManifest struct {
+	MaxTokens int                  `json:"max_tokens"`
+	Parts     int                  `json:"parts"`
+	Files     []chunkManifestEntry `json:"files"`
+}
+
+// chunkWriter accumulates markdown into numbered parts, flushing to disk
+// whenever the next addition would push a part over maxTokens.
+type chunkWriter struct {
+	outputFile string
+	maxTokens  int
+	partIndex  int
+	buf        strings.Builder
+	tokens     int
+	manifest   chunkManifest
+}
+
+func newChunkWriter(outputFile string, maxTokens int) *chunkWriter {
+	return &chunkWriter{
+		outputFile: outputFile,
+		maxTokens:  maxTokens,
+		partIndex:  1,
+		manifest:   chunkManifest{MaxTokens: maxTokens},
+	}
+}
+
+func (w *chunkWriter) writeHeader(tree string) {
+	header := "# Code Collection Report\n\n" +
+		"## Directory Structure\n\n```plaintext\n" +
+		tree +
+		"```\n\n## File Contents\n\n"
+	w.buf.WriteString(header)
+	w.tokens += estimateTokens(header)
+}
+
+// append adds s to the current part, flushing first if s wouldn't fit and
+// the part already has content, then records file in the manifest under
+// whichever part it ended up in.
+func (w *chunkWriter) append(s string, tokens int, file string, split bool) error {
+	if w.maxTokens > 0 && w.tokens > 0 && w.tokens+tokens > w.maxTokens {
+		if err := w.flush(); err != nil {
+			return err
+		}
+	}
+	w.buf.WriteString(s)
+	w.tokens += tokens
+	w.manifest.Files = append(w.manifest.Files, chunkManifestEntry{File: file, Part: w.partIndex, Split: split})
+	return nil
+}
+
+func (w *chunkWriter) flush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	path := fmt.Sprintf("%s.part%d.md", w.outputFile, w.partIndex)
+	if err := os.WriteFile(path, []byte(w.buf.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	w.partIndex++
+	w.buf.Reset()
+	w.tokens = 0
+	return nil
+}
+
+func (w *chunkWriter) finish() error {
+	if err := w.flush(); err != nil {
+		return err
+	}
+	w.manifest.Parts = w.partIndex - 1
+
+	manifestBytes, err := json.MarshalIndent(w.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(w.outputFile+".manifest.json", manifestBytes, 0o644)
+}
+
+// exportMarkdownChunked writes data across one or more numbered markdown
+// parts (outputFile.part1.md, .part2.md, ...) so no part exceeds maxTokens,
+// plus an outputFile.manifest.json recording which part each file landed
+// in. Whole files are kept together where possible; a single file that
+// alone exceeds maxTokens is split at line boundaries across as many parts
+// as it needs. Lines are never split, so a part holding a single line (or a
+// directory tree) that alone exceeds maxTokens exceeds it too. A maxTokens
+// of 0 or less means unbounded: everything lands whole in one part.
+func exportMarkdownChunked(data collector.CollectedData, outputFile string, maxTokens int) error {
+	w := newChunkWriter(outputFile, maxTokens)
+	w.writeHeader(data.Tree)
+
+	for _, fileData := range data.Files {
+		language := detectLanguage(fileData)
+		section := fmt.Sprintf("### %s\n\n```%s\n%s\n```\n\n", fileData.RelativePath, language, fileData.Content)
+		tokens := estimateTokens(section)
+
+		if maxTokens > 0 && tokens > maxTokens {
+			if err := writeSplitSections(w, fileData, language, maxTokens); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := w.append(section, tokens, fileData.RelativePath, false); err != nil {
+			return err
+		}
+	}
+
+	return w.finish()
+}
+
+// writeSplitSections breaks a single file that alone exceeds maxTokens into
+// multiple fenced fragments at line boundaries, each landing in whichever
+// part has room.
+func writeSplitSections(w *chunkWriter, fileData collector.FileData, language string, maxTokens int) error {
+	opening := fmt.Sprintf("### %s\n\n```%s\n", fileData.RelativePath, language)
+	continuation := fmt.Sprintf("### %s (continued)\n\n```%s\n", fileData.RelativePath, language)
+	const footer = "\n```\n\n"
+
+	header := opening
+	var chunk strings.Builder
+	chunk.WriteString(header)
+	chunkTokens := estimateTokens(header)
+
+	flushChunk := func() error {
+		chunk.WriteString(footer)
+		if err := w.append(chunk.String(), chunkTokens+estimateTokens(footer), fileData.RelativePath, true); err != nil {
+			return err
+		}
+		header = continuation
+		chunk.Reset()
+		chunk.WriteString(header)
+		chunkTokens = estimateTokens(header)
+		return nil
+	}
+
+	for _, line := range strings.Split(fileData.Content, "\n") {
+		lineTokens := estimateTokens(line) + 1
+		if chunkTokens > estimateTokens(header) && chunkTokens+lineTokens+estimateTokens(footer) > maxTokens {
+			if err := flushChunk(); err != nil {
+				return err
+			}
+		}
+		chunk.WriteString(line)
+		chunk.WriteString("\n")
+		chunkTokens += lineTokens
+	}
+
+	return flushChunk()
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/Anomalybound/codecollector/pkg/collector"
+)
+
+func TestExportJSONLStreamsTreeThenFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go": {Data: []byte("package main")},
+	}
+	c, err := collector.NewFromFS(fsys, collector.Config{})
+	if err != nil {
+		t.Fatalf("NewFromFS: %v", err)
+	}
+
+	outputFile := filepath.Join(t.TempDir(), "out.jsonl")
+	if err := exportJSONL(context.Background(), c, outputFile); err != nil {
+		t.Fatalf("exportJSONL: %v", err)
+	}
+
+	f, err := os.Open(outputFile)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2 (tree + one file): %v", len(lines), lines)
+	}
+
+	var tree treeRecord
+	if err := json.Unmarshal([]byte(lines[0]), &tree); err != nil {
+		t.Fatalf("unmarshalling tree record: %v", err)
+	}
+	if tree.Type != "tree" {
+		t.Fatalf("first record type = %q, want \"tree\"", tree.Type)
+	}
+
+	var file fileRecord
+	if err := json.Unmarshal([]byte(lines[1]), &file); err != nil {
+		t.Fatalf("unmarshalling file record: %v", err)
+	}
+	if file.Type != "file" || file.RelativePath != "main.go" {
+		t.Fatalf("got file record %+v, want type=file relative_path=main.go", file)
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/Anomalybound/codecollector/pkg/collector"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	config  collector.Config
+	rootCmd = &cobra.Command{Use: "codecollector"}
+)
+
+var version string // This will be set by the linker at build time
+
+func main() {
+	log.SetOutput(os.Stdout)
+	log.SetFlags(log.Ltime | log.Lshortfile)
+
+	rootCmd.PersistentFlags().BoolP("version", "v", false, "Print the version number of Code Collector")
+	rootCmd.PersistentFlags().StringP("directory", "d", "", "Path of the folder to traverse")
+	rootCmd.PersistentFlags().StringP("output", "o", "collected_code", "Output file name without extension")
+	rootCmd.PersistentFlags().String("config", "", "Path to configuration file (YAML)")
+	rootCmd.PersistentFlags().String("repo", "", "Repository URL to clone and process (GitHub, GitLab, Gitea, Bitbucket, ssh://, file://, or a local path)")
+	rootCmd.PersistentFlags().String("ref", "", "Branch, tag, or commit SHA to check out (defaults to the remote's default branch)")
+	rootCmd.PersistentFlags().String("subpath", "", "Restrict traversal to this subtree of the cloned repo")
+	rootCmd.PersistentFlags().String("ssh-key", "", "Path to an SSH private key, for ssh:// repo URLs")
+	rootCmd.PersistentFlags().String("token", "", "Bearer/basic auth token, for http(s):// repo URLs")
+	rootCmd.PersistentFlags().String("token-env", "", "Environment variable to read the auth token from, if --token is unset")
+	rootCmd.PersistentFlags().String("output-format", "json", "Output format (json, text, markdown, or jsonl)")
+	rootCmd.PersistentFlags().Int("concurrency", 0, "Maximum number of files read concurrently (0 = runtime.NumCPU())")
+	rootCmd.PersistentFlags().Bool("include-vendored", false, "Include files identified as vendored (linguist-vendored, enry.IsVendor)")
+	rootCmd.PersistentFlags().Bool("include-generated", false, "Include files identified as generated (linguist-generated, enry.IsGenerated)")
+	rootCmd.PersistentFlags().Int64("max-file-bytes", 0, "Replace content above this many bytes with a placeholder stub (0 = unlimited)")
+	rootCmd.PersistentFlags().Int("max-tokens", 0, "Split markdown output into numbered parts so none exceeds this many estimated tokens (0 = unlimited)")
+	rootCmd.PersistentFlags().String("diff", "", "Collect only files changed between two refs, as BASE..HEAD")
+	rootCmd.PersistentFlags().String("since", "", "Collect only files changed between <ref> and HEAD; shorthand for --diff <ref>..HEAD")
+	rootCmd.PersistentFlags().String("diff-context", "full", "Content for changed files in --diff/--since mode: full or patch")
+
+	rootCmd.RunE = runCodeCollector
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func runCodeCollector(cmd *cobra.Command, args []string) error {
+	// Load default config if it exists
+	if _, err := os.Stat("config.yaml"); err == nil {
+		if err := loadConfig("config.yaml"); err != nil {
+			return err
+		}
+	}
+
+	versionFlag, _ := cmd.Flags().GetBool("version")
+	if versionFlag {
+		fmt.Printf("Code Collector version %s\n", version)
+		return nil
+	}
+
+	// Override with user-specified config if provided
+	configPath, _ := cmd.Flags().GetString("config")
+	if configPath != "" {
+		if err := loadConfig(configPath); err != nil {
+			return err
+		}
+	}
+
+	directory, _ := cmd.Flags().GetString("directory")
+	repoURL, _ := cmd.Flags().GetString("repo")
+
+	var rootDir string
+	if repoURL != "" {
+		ref, _ := cmd.Flags().GetString("ref")
+		subpath, _ := cmd.Flags().GetString("subpath")
+		sshKey, _ := cmd.Flags().GetString("ssh-key")
+		token, _ := cmd.Flags().GetString("token")
+		tokenEnv, _ := cmd.Flags().GetString("token-env")
+
+		walkDir, cloneDir, err := cloneRepo(repoURL, cloneOptions{
+			Ref:      ref,
+			Subpath:  subpath,
+			SSHKey:   sshKey,
+			Token:    token,
+			TokenEnv: tokenEnv,
+		})
+		if err != nil {
+			return err
+		}
+		rootDir = walkDir
+		defer os.RemoveAll(cloneDir)
+	} else {
+		rootDir = directory
+	}
+
+	outputFormat, _ := cmd.Flags().GetString("output-format")
+	outputFile, _ := cmd.Flags().GetString("output")
+	maxTokens, _ := cmd.Flags().GetInt("max-tokens")
+
+	includeVendored, _ := cmd.Flags().GetBool("include-vendored")
+	includeGenerated, _ := cmd.Flags().GetBool("include-generated")
+	maxFileBytes, _ := cmd.Flags().GetInt64("max-file-bytes")
+
+	diffFlag, _ := cmd.Flags().GetString("diff")
+	sinceFlag, _ := cmd.Flags().GetString("since")
+
+	var collectedData collector.CollectedData
+	if diffFlag != "" || sinceFlag != "" {
+		if outputFormat == "jsonl" {
+			return fmt.Errorf("--output-format jsonl is not supported with --diff/--since")
+		}
+
+		base, head, err := parseDiffRange(diffFlag, sinceFlag)
+		if err != nil {
+			return err
+		}
+
+		diffCtx := collector.DiffContextFull
+		if contextFlag, _ := cmd.Flags().GetString("diff-context"); contextFlag == "patch" {
+			diffCtx = collector.DiffContextPatch
+		}
+
+		collectedData, err = collector.DiffCollect(rootDir, base, head, collector.DiffOptions{
+			Config:           config,
+			IncludeVendored:  includeVendored,
+			IncludeGenerated: includeGenerated,
+			MaxFileBytes:     maxFileBytes,
+		}, diffCtx)
+		if err != nil {
+			return err
+		}
+	} else {
+		c, err := collector.New(rootDir, config)
+		if err != nil {
+			return err
+		}
+		c.Concurrency, _ = cmd.Flags().GetInt("concurrency")
+		c.IncludeVendored = includeVendored
+		c.IncludeGenerated = includeGenerated
+		c.MaxFileBytes = maxFileBytes
+
+		if outputFormat == "jsonl" {
+			return exportJSONL(cmd.Context(), c, outputFile+".jsonl")
+		}
+
+		collectedData, err = c.Collect(cmd.Context())
+		if err != nil {
+			return err
+		}
+	}
+
+	if maxTokens > 0 {
+		if outputFormat != "markdown" {
+			return fmt.Errorf("--max-tokens is only supported with --output-format markdown")
+		}
+		return exportMarkdownChunked(collectedData, outputFile, maxTokens)
+	}
+
+	return exportOutput(collectedData, outputFile, outputFormat)
+}
+
+// parseDiffRange resolves the --diff/--since flags into a (base, head) pair.
+func parseDiffRange(diffFlag, sinceFlag string) (base, head string, err error) {
+	if diffFlag != "" {
+		parts := strings.SplitN(diffFlag, "..", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return "", "", fmt.Errorf("--diff must be of the form BASE..HEAD, got %q", diffFlag)
+		}
+		return parts[0], parts[1], nil
+	}
+	return sinceFlag, "HEAD", nil
+}
+
+func loadConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, &config)
+}
@@ -0,0 +1,103 @@
+package collector
+
+import (
+	"io/fs"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// attributeRule pairs a path pattern (gitattributes reuses gitignore's glob
+// syntax) with the attributes assigned to matching paths.
+type attributeRule struct {
+	pattern    gitignore.Pattern
+	attributes map[string]string
+}
+
+// attributeSet resolves gitattributes values, such as linguist-generated or
+// linguist-vendored, for paths under a root. Rules are collected from every
+// .gitattributes file found while walking fsys; later rules win, matching
+// git's own "last match wins" precedence.
+type attributeSet struct {
+	rules []attributeRule
+}
+
+func buildAttributeSet(fsys fs.FS) *attributeSet {
+	var rules []attributeRule
+
+	_ = fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if d.Name() == gitDir {
+			return fs.SkipDir
+		}
+		gitattributesPath := p + "/.gitattributes"
+		if p == "." {
+			gitattributesPath = ".gitattributes"
+		}
+		content, err := fs.ReadFile(fsys, gitattributesPath)
+		if err != nil {
+			return nil
+		}
+
+		var domain []string
+		if p != "." {
+			domain = strings.Split(p, "/")
+		}
+
+		for _, line := range strings.Split(string(content), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			fields := strings.Fields(line)
+			if len(fields) < 2 {
+				continue
+			}
+
+			attrs := make(map[string]string, len(fields)-1)
+			for _, field := range fields[1:] {
+				name, value := field, "true"
+				switch {
+				case strings.HasPrefix(field, "-"):
+					name, value = field[1:], "false"
+				case strings.Contains(field, "="):
+					parts := strings.SplitN(field, "=", 2)
+					name, value = parts[0], parts[1]
+				}
+				attrs[name] = value
+			}
+
+			rules = append(rules, attributeRule{
+				pattern:    gitignore.ParsePattern(fields[0], domain),
+				attributes: attrs,
+			})
+		}
+		return nil
+	})
+
+	return &attributeSet{rules: rules}
+}
+
+// Get returns the resolved value of attr for path and whether any rule set
+// it, walking rules in file order so later .gitattributes entries override
+// earlier ones.
+func (a *attributeSet) Get(path, attr string) (string, bool) {
+	if a == nil {
+		return "", false
+	}
+
+	parts := strings.Split(path, "/")
+	var value string
+	var ok bool
+	for _, rule := range a.rules {
+		v, present := rule.attributes[attr]
+		if !present || rule.pattern.Match(parts, false) == gitignore.NoMatch {
+			continue
+		}
+		value, ok = v, true
+	}
+	return value, ok
+}
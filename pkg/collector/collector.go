@@ -0,0 +1,312 @@
+// Package collector walks a directory (or any fs.FS) and gathers the files
+// that should be handed to downstream tooling such as an LLM prompt builder.
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/go-enry/go-enry/v2"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// Collector walks a root and reports the files that pass its Config's
+// extension filter and ignore rules. FS is exposed so callers can point it
+// at anything implementing fs.FS, including fstest.MapFS in tests.
+type Collector struct {
+	Config      Config
+	IgnoreRules []IgnoreRule
+	FS          fs.FS
+
+	// Concurrency caps how many files Walk reads at once. Zero means
+	// runtime.NumCPU().
+	Concurrency int
+
+	// IncludeVendored keeps paths that .gitattributes marks
+	// linguist-vendored or that enry.IsVendor recognizes as vendored.
+	IncludeVendored bool
+	// IncludeGenerated keeps paths that .gitattributes marks
+	// linguist-generated or that enry.IsGenerated recognizes as generated.
+	IncludeGenerated bool
+
+	// MaxFileBytes caps how much of a file's content is collected. Files
+	// larger than this are still reported, with Content replaced by a
+	// placeholder stub, so the tree stays complete. Zero means unlimited.
+	MaxFileBytes int64
+
+	attrs  *attributeSet
+	ignore *ignoreMatcher
+}
+
+// New builds a Collector rooted at rootDir on the local filesystem. The
+// ignore engine (global excludesfile, user-config patterns, and every
+// .gitignore under rootDir) is resolved once up front and baked into FS.
+func New(rootDir string, cfg Config) (*Collector, error) {
+	absRoot, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil, fmt.Errorf("resolving root directory: %w", err)
+	}
+
+	return newFromFS(os.DirFS(absRoot), cfg, true)
+}
+
+// NewFromFS builds a Collector over an arbitrary fs.FS, wrapping it with the
+// ignore engine. This is the entry point tests use with fstest.MapFS. The
+// global excludesfile (~/.gitconfig, ~/.config/git/ignore) is real-disk
+// state unrelated to fsys, so it's never consulted here -- only New, which
+// is backed by the real filesystem, reads it.
+func NewFromFS(fsys fs.FS, cfg Config) (*Collector, error) {
+	return newFromFS(fsys, cfg, false)
+}
+
+func newFromFS(fsys fs.FS, cfg Config, includeGlobalExcludes bool) (*Collector, error) {
+	var rules []IgnoreRule
+	for _, pattern := range cfg.IgnorePatterns {
+		if pattern != "" {
+			rules = append(rules, IgnoreRule{Pattern: pattern, Source: "user-config"})
+		}
+	}
+
+	matcher := &ignoreMatcher{patterns: buildIgnorePatterns(fsys, rules, includeGlobalExcludes)}
+
+	return &Collector{
+		Config:      cfg,
+		IgnoreRules: rules,
+		FS:          newIgnoreFS(fsys, matcher),
+		attrs:       buildAttributeSet(fsys),
+		ignore:      matcher,
+	}, nil
+}
+
+// Walk streams FileData for every included file under the collector's root.
+// The returned channel is closed once the walk completes, ctx is cancelled,
+// or an error is encountered.
+func (c *Collector) Walk(ctx context.Context) (<-chan FileData, error) {
+	out := make(chan FileData)
+
+	concurrency := c.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+	sem := make(chan struct{}, concurrency)
+
+	go func() {
+		defer close(out)
+
+		var wg sync.WaitGroup
+		err := fs.WalkDir(c.FS, ".", func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if d.IsDir() || !c.isIncluded(p) {
+				return nil
+			}
+
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				data, err := c.readFile(p)
+				var skipped *skippedFileError
+				if errors.As(err, &skipped) {
+					log.Printf("Skipping %s file: %s", skipped.Reason, skipped.Path)
+					return
+				}
+				if err != nil {
+					log.Printf("Error processing file %s: %v", p, err)
+					return
+				}
+				select {
+				case out <- data:
+				case <-ctx.Done():
+				}
+			}()
+			return nil
+		})
+		wg.Wait()
+		if err != nil {
+			log.Printf("Error walking collector FS: %v", err)
+		}
+	}()
+
+	return out, nil
+}
+
+// Tree renders an indented directory listing of everything the collector
+// would include, the same shape previously embedded in CollectedData.Tree.
+func (c *Collector) Tree(ctx context.Context) (string, error) {
+	var output strings.Builder
+
+	err := fs.WalkDir(c.FS, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == "." {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		indent := strings.Repeat("  ", strings.Count(p, "/"))
+		if d.IsDir() {
+			output.WriteString(fmt.Sprintf("%s%s/\n", indent, d.Name()))
+		} else if c.isIncluded(p) {
+			output.WriteString(fmt.Sprintf("%s%s\n", indent, d.Name()))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("generating tree: %w", err)
+	}
+
+	return output.String(), nil
+}
+
+// Collect runs Tree and Walk together and buffers the result, matching the
+// shape the original command-line tool produced.
+func (c *Collector) Collect(ctx context.Context) (CollectedData, error) {
+	var data CollectedData
+
+	tree, err := c.Tree(ctx)
+	if err != nil {
+		return data, err
+	}
+	data.Tree = tree
+
+	files, err := c.Walk(ctx)
+	if err != nil {
+		return data, err
+	}
+	for fileData := range files {
+		data.Files = append(data.Files, fileData)
+	}
+
+	return data, nil
+}
+
+func (c *Collector) isIncluded(p string) bool {
+	if !c.passesAttributeFilters(p) {
+		return false
+	}
+	if len(c.Config.IncludeExtensions) == 0 {
+		return true
+	}
+	ext := path.Ext(p)
+	for _, included := range c.Config.IncludeExtensions {
+		if ext == included {
+			return true
+		}
+	}
+	return false
+}
+
+// passesAttributeFilters applies the binary/vendored/generated exclusions
+// that can be decided from the path and .gitattributes alone, without
+// reading file content. readFile applies the content-based enry checks once
+// the bytes are in hand.
+func (c *Collector) passesAttributeFilters(p string) bool {
+	if binary, ok := c.attrs.Get(p, "binary"); ok && binary == "true" {
+		return false
+	}
+	if generated, ok := c.attrs.Get(p, "linguist-generated"); ok && generated == "true" && !c.IncludeGenerated {
+		return false
+	}
+	if vendored, ok := c.attrs.Get(p, "linguist-vendored"); ok && vendored == "true" && !c.IncludeVendored {
+		return false
+	}
+
+	// A path the gitignore matcher explicitly includes (e.g. a "!vendor/keep.go"
+	// negation) takes precedence over the vendor/generated *heuristics* below --
+	// enry.IsVendor and enry.IsGenerated only guess from the path, and that guess
+	// shouldn't silently undo an explicit ignore-file decision. It doesn't
+	// bypass the .gitattributes checks above, which are themselves explicit.
+	if c.gitignoreResult(p) == gitignore.Include {
+		return true
+	}
+
+	if !c.IncludeVendored && enry.IsVendor(p) {
+		return false
+	}
+	if !c.IncludeGenerated && enry.IsGenerated(p, nil) {
+		return false
+	}
+	return true
+}
+
+// gitignoreResult reports how the collector's gitignore patterns resolve p,
+// independent of the .gitattributes/enry checks layered on top of it.
+func (c *Collector) gitignoreResult(p string) gitignore.MatchResult {
+	if c.ignore == nil {
+		return gitignore.NoMatch
+	}
+	return c.ignore.MatchResult(strings.Split(p, "/"), false)
+}
+
+// skippedFileError marks a file that was deliberately excluded once its
+// content was available (binary or generated), as opposed to a genuine read
+// failure.
+type skippedFileError struct {
+	Path   string
+	Reason string
+}
+
+func (e *skippedFileError) Error() string {
+	return fmt.Sprintf("%s: %s file, skipping", e.Path, e.Reason)
+}
+
+func (c *Collector) readFile(p string) (FileData, error) {
+	content, err := fs.ReadFile(c.FS, p)
+	if err != nil {
+		return FileData{}, err
+	}
+
+	resolved, err := c.filterContent(p, content)
+	if err != nil {
+		return FileData{}, err
+	}
+
+	return FileData{
+		RelativePath: filepath.FromSlash(p),
+		Content:      resolved,
+	}, nil
+}
+
+// filterContent applies the content-dependent checks -- the size cap,
+// binary detection, generated detection -- once a file's bytes are in hand.
+// It returns either the content to collect (possibly a placeholder stub) or
+// a *skippedFileError. Both readFile and DiffCollect share this so a file
+// is treated the same way regardless of which path produced its bytes.
+func (c *Collector) filterContent(p string, content []byte) (string, error) {
+	if c.MaxFileBytes > 0 && int64(len(content)) > c.MaxFileBytes {
+		return fmt.Sprintf("<file too large: %.1fMB, skipped>", float64(len(content))/(1024*1024)), nil
+	}
+
+	if enry.IsBinary(content) {
+		return "", &skippedFileError{Path: p, Reason: "binary"}
+	}
+	if !c.IncludeGenerated && enry.IsGenerated(p, content) {
+		return "", &skippedFileError{Path: p, Reason: "generated"}
+	}
+
+	return string(content), nil
+}
@@ -0,0 +1,175 @@
+package collector
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func collectPaths(t *testing.T, c *Collector) []string {
+	t.Helper()
+
+	files, err := c.Walk(context.Background())
+	if err != nil {
+		t.Fatalf("Walk: %v", err)
+	}
+
+	var got []string
+	for f := range files {
+		got = append(got, f.RelativePath)
+	}
+	sort.Strings(got)
+	return got
+}
+
+func TestWalkRespectsGitignore(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":       {Data: []byte("package main")},
+		"build/out.bin": {Data: []byte("binary junk")},
+		".gitignore":    {Data: []byte("build/\n")},
+	}
+
+	c, err := NewFromFS(fsys, Config{})
+	if err != nil {
+		t.Fatalf("NewFromFS: %v", err)
+	}
+
+	got := collectPaths(t, c)
+	want := []string{"main.go"}
+	if len(got) != len(want) || got[0] != want[0] {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestWalkGitignoreNegation(t *testing.T) {
+	fsys := fstest.MapFS{
+		"vendor/keep.go": {Data: []byte("package vendor")},
+		"vendor/drop.go": {Data: []byte("package vendor")},
+		".gitignore":     {Data: []byte("vendor/*\n!vendor/keep.go\n")},
+	}
+
+	c, err := NewFromFS(fsys, Config{})
+	if err != nil {
+		t.Fatalf("NewFromFS: %v", err)
+	}
+
+	got := collectPaths(t, c)
+	want := []string{"vendor/keep.go"}
+	if len(got) != len(want) || got[0] != want[0] {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestWalkAlwaysExcludesGitDir(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":             {Data: []byte("package main")},
+		".git/config":         {Data: []byte("[core]\n")},
+		".git/logs/HEAD":      {Data: []byte("reflog entry")},
+		".git/COMMIT_EDITMSG": {Data: []byte("message")},
+	}
+
+	// No .gitignore entry for .git at all -- real git never needs one, and
+	// the collector must exclude it unconditionally rather than relying on
+	// ignore-file content.
+	c, err := NewFromFS(fsys, Config{})
+	if err != nil {
+		t.Fatalf("NewFromFS: %v", err)
+	}
+
+	got := collectPaths(t, c)
+	want := []string{"main.go"}
+	if len(got) != len(want) || got[0] != want[0] {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	tree, err := c.Tree(context.Background())
+	if err != nil {
+		t.Fatalf("Tree: %v", err)
+	}
+	if strings.Contains(tree, ".git") {
+		t.Fatalf("tree should never mention .git:\n%s", tree)
+	}
+}
+
+func TestIsIncludedExtensionFilter(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":   {Data: []byte("package main")},
+		"README.md": {Data: []byte("# readme")},
+	}
+
+	c, err := NewFromFS(fsys, Config{IncludeExtensions: []string{".go"}})
+	if err != nil {
+		t.Fatalf("NewFromFS: %v", err)
+	}
+
+	got := collectPaths(t, c)
+	want := []string{"main.go"}
+	if len(got) != len(want) || got[0] != want[0] {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestPassesAttributeFiltersExcludesGenerated(t *testing.T) {
+	fsys := fstest.MapFS{
+		"gen.go":         {Data: []byte("package main")},
+		"plain.go":       {Data: []byte("package main")},
+		".gitattributes": {Data: []byte("gen.go linguist-generated=true\n")},
+	}
+
+	c, err := NewFromFS(fsys, Config{})
+	if err != nil {
+		t.Fatalf("NewFromFS: %v", err)
+	}
+
+	got := collectPaths(t, c)
+	want := []string{"plain.go"}
+	if len(got) != len(want) || got[0] != want[0] {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestPassesAttributeFiltersIncludeGeneratedOverride(t *testing.T) {
+	fsys := fstest.MapFS{
+		"gen.go":         {Data: []byte("package main")},
+		".gitattributes": {Data: []byte("gen.go linguist-generated=true\n")},
+	}
+
+	c, err := NewFromFS(fsys, Config{})
+	if err != nil {
+		t.Fatalf("NewFromFS: %v", err)
+	}
+	c.IncludeGenerated = true
+
+	got := collectPaths(t, c)
+	want := []string{"gen.go"}
+	if len(got) != len(want) || got[0] != want[0] {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestTreeListsDirectoriesAndIncludedFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"src/main.go":     {Data: []byte("package main")},
+		"src/ignored.bin": {Data: []byte("binary junk")},
+		".gitignore":      {Data: []byte("*.bin\n")},
+	}
+
+	c, err := NewFromFS(fsys, Config{})
+	if err != nil {
+		t.Fatalf("NewFromFS: %v", err)
+	}
+
+	tree, err := c.Tree(context.Background())
+	if err != nil {
+		t.Fatalf("Tree: %v", err)
+	}
+
+	if !strings.Contains(tree, "src/\n") || !strings.Contains(tree, "main.go\n") {
+		t.Fatalf("tree missing expected entries:\n%s", tree)
+	}
+	if strings.Contains(tree, "ignored.bin") {
+		t.Fatalf("tree should not include ignored file:\n%s", tree)
+	}
+}
@@ -0,0 +1,200 @@
+package collector
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/utils/merkletrie"
+)
+
+// DiffContext selects how DiffCollect renders a changed file's content.
+type DiffContext int
+
+const (
+	// DiffContextFull emits the complete post-image content of each
+	// added/modified file. Deleted files never get a content block.
+	DiffContextFull DiffContext = iota
+	// DiffContextPatch emits a unified diff for each added/modified file
+	// instead of its full content.
+	DiffContextPatch
+)
+
+// DiffOptions configures the Collector that DiffCollect builds internally,
+// so changed paths are filtered exactly like a normal collection run:
+// extensions, .gitignore (including Config.IgnorePatterns), and the
+// .gitattributes/enry binary-vendored-generated checks all apply.
+type DiffOptions struct {
+	Config           Config
+	IncludeVendored  bool
+	IncludeGenerated bool
+	MaxFileBytes     int64
+}
+
+// DiffCollect resolves base and head (anything
+// git.Repository.ResolveRevision accepts: branch, tag, or commit SHA) in the
+// git repository containing rootDir, and returns a CollectedData limited to
+// the files that changed between them and that the equivalent Collector
+// would include. rootDir may be the repo root itself or any subdirectory of
+// it (e.g. one narrowed by --subpath); the subtree outside rootDir is
+// excluded from the result the same way a normal collection rooted there
+// would be. Deleted files appear in the tree with a strikethrough marker
+// and no content block.
+func DiffCollect(rootDir, base, head string, opts DiffOptions, diffCtx DiffContext) (CollectedData, error) {
+	var data CollectedData
+
+	absRoot, err := filepath.Abs(rootDir)
+	if err != nil {
+		return data, fmt.Errorf("resolving root directory: %w", err)
+	}
+
+	repo, err := git.PlainOpenWithOptions(absRoot, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return data, fmt.Errorf("opening repo containing %s: %w", absRoot, err)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return data, fmt.Errorf("resolving worktree: %w", err)
+	}
+	repoRoot := wt.Filesystem.Root()
+
+	subpath, err := filepath.Rel(repoRoot, absRoot)
+	if err != nil {
+		return data, fmt.Errorf("resolving %s relative to repo root %s: %w", absRoot, repoRoot, err)
+	}
+	if subpath == "." {
+		subpath = ""
+	}
+	subpath = filepath.ToSlash(subpath)
+
+	c, err := New(repoRoot, opts.Config)
+	if err != nil {
+		return data, err
+	}
+	c.IncludeVendored = opts.IncludeVendored
+	c.IncludeGenerated = opts.IncludeGenerated
+	c.MaxFileBytes = opts.MaxFileBytes
+
+	baseTree, err := resolveTree(repo, base)
+	if err != nil {
+		return data, fmt.Errorf("resolving base %q: %w", base, err)
+	}
+	headTree, err := resolveTree(repo, head)
+	if err != nil {
+		return data, fmt.Errorf("resolving head %q: %w", head, err)
+	}
+
+	changes, err := baseTree.Diff(headTree)
+	if err != nil {
+		return data, fmt.Errorf("diffing %s..%s: %w", base, head, err)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changePath(changes[i]) < changePath(changes[j])
+	})
+
+	var tree strings.Builder
+	for _, change := range changes {
+		fullPath := changePath(change)
+
+		relPath, ok := trimSubpath(fullPath, subpath)
+		if !ok || c.gitignoreResult(fullPath) == gitignore.Exclude || !c.isIncluded(fullPath) {
+			continue
+		}
+
+		action, err := change.Action()
+		if err != nil {
+			return data, err
+		}
+		if action == merkletrie.Delete {
+			tree.WriteString("~~" + relPath + "~~\n")
+			continue
+		}
+		tree.WriteString(relPath + "\n")
+
+		content, err := renderChange(c, change, diffCtx)
+		var skipped *skippedFileError
+		if errors.As(err, &skipped) {
+			continue
+		}
+		if err != nil {
+			return data, fmt.Errorf("rendering %s: %w", relPath, err)
+		}
+		data.Files = append(data.Files, FileData{RelativePath: relPath, Content: content})
+	}
+	data.Tree = tree.String()
+
+	return data, nil
+}
+
+// trimSubpath reports whether path falls under subpath and, if so, returns
+// it relative to subpath. An empty subpath matches everything unchanged.
+func trimSubpath(path, subpath string) (string, bool) {
+	if subpath == "" {
+		return path, true
+	}
+	prefix := subpath + "/"
+	if !strings.HasPrefix(path, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(path, prefix), true
+}
+
+func resolveTree(repo *git.Repository, rev string) (*object.Tree, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, err
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, err
+	}
+	return commit.Tree()
+}
+
+// changePath picks whichever side of the change has a name: the post-image
+// path for adds/modifies, the pre-image path for deletes.
+func changePath(change *object.Change) string {
+	if change.To.Name != "" {
+		return change.To.Name
+	}
+	return change.From.Name
+}
+
+// renderChange produces the content to collect for an added/modified
+// change, running the post-image content through the same filterContent
+// checks Walk applies (size cap, binary, generated) regardless of diffCtx,
+// so a too-large/binary/generated file is treated identically whether the
+// caller asked for full content or a patch.
+func renderChange(c *Collector, change *object.Change, diffCtx DiffContext) (string, error) {
+	f, err := change.To.Tree.TreeEntryFile(&change.To.TreeEntry)
+	if err != nil {
+		return "", err
+	}
+	content, err := f.Contents()
+	if err != nil {
+		return "", err
+	}
+
+	filtered, err := c.filterContent(changePath(change), []byte(content))
+	if err != nil {
+		return "", err
+	}
+	if diffCtx == DiffContextFull || filtered != content {
+		// Either full content was asked for, or filterContent substituted a
+		// placeholder (e.g. the size cap) that applies to patches too.
+		return filtered, nil
+	}
+
+	patch, err := change.Patch()
+	if err != nil {
+		return "", err
+	}
+	return patch.String(), nil
+}
@@ -0,0 +1,127 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// initTestRepo creates a git repo under t.TempDir() with an initial commit
+// and returns the repo root and its *git.Repository handle.
+func initTestRepo(t *testing.T, files map[string]string) (string, *git.Repository) {
+	t.Helper()
+
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("PlainInit: %v", err)
+	}
+
+	commitFiles(t, repo, dir, files, "initial commit")
+	return dir, repo
+}
+
+// commitFiles writes files into dir, stages them, and commits them to repo.
+func commitFiles(t *testing.T, repo *git.Repository, dir string, files map[string]string, message string) {
+	t.Helper()
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Worktree: %v", err)
+	}
+
+	for name, content := range files {
+		full := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatalf("MkdirAll: %v", err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatalf("WriteFile %s: %v", name, err)
+		}
+		if _, err := wt.Add(filepath.ToSlash(name)); err != nil {
+			t.Fatalf("Add %s: %v", name, err)
+		}
+	}
+
+	sig := &object.Signature{Name: "test", Email: "test@example.com", When: time.Unix(0, 0)}
+	if _, err := wt.Commit(message, &git.CommitOptions{Author: sig, Committer: sig}); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+}
+
+func TestDiffCollectRelativeRootDir(t *testing.T) {
+	dir, repo := initTestRepo(t, map[string]string{"main.go": "package main\n"})
+	commitFiles(t, repo, dir, map[string]string{"main.go": "package main\n\nfunc main() {}\n"}, "second commit")
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Chdir: %v", err)
+	}
+
+	// "." is the natural --directory value from inside a repo; DiffCollect
+	// must resolve it to an absolute path before computing the subpath
+	// instead of crashing on filepath.Rel with a relative rootDir.
+	data, err := DiffCollect(".", "HEAD~1", "HEAD", DiffOptions{}, DiffContextFull)
+	if err != nil {
+		t.Fatalf("DiffCollect: %v", err)
+	}
+
+	if !strings.Contains(data.Tree, "main.go") {
+		t.Fatalf("tree missing main.go:\n%s", data.Tree)
+	}
+	if len(data.Files) != 1 || data.Files[0].RelativePath != "main.go" {
+		t.Fatalf("got files %+v, want one file main.go", data.Files)
+	}
+}
+
+func TestDiffCollectHonorsConfigIgnorePatterns(t *testing.T) {
+	dir, repo := initTestRepo(t, map[string]string{
+		"main.go":   "package main\n",
+		"secret.go": "package main\n\nconst key = \"a\"\n",
+	})
+	commitFiles(t, repo, dir, map[string]string{
+		"main.go":   "package main\n\nfunc main() {}\n",
+		"secret.go": "package main\n\nconst key = \"b\"\n",
+	}, "second commit")
+
+	opts := DiffOptions{Config: Config{IgnorePatterns: []string{"secret.go"}}}
+	data, err := DiffCollect(dir, "HEAD~1", "HEAD", opts, DiffContextFull)
+	if err != nil {
+		t.Fatalf("DiffCollect: %v", err)
+	}
+
+	for _, f := range data.Files {
+		if f.RelativePath == "secret.go" {
+			t.Fatalf("secret.go should have been filtered out by IgnorePatterns, got %+v", data.Files)
+		}
+	}
+	if strings.Contains(data.Tree, "secret.go") {
+		t.Fatalf("tree should not mention secret.go:\n%s", data.Tree)
+	}
+}
+
+func TestDiffCollectPatchContextRespectsMaxFileBytes(t *testing.T) {
+	dir, repo := initTestRepo(t, map[string]string{"big.go": "package main\n"})
+	commitFiles(t, repo, dir, map[string]string{"big.go": strings.Repeat("x", 100)}, "grow the file")
+
+	opts := DiffOptions{MaxFileBytes: 10}
+	data, err := DiffCollect(dir, "HEAD~1", "HEAD", opts, DiffContextPatch)
+	if err != nil {
+		t.Fatalf("DiffCollect: %v", err)
+	}
+	if len(data.Files) != 1 {
+		t.Fatalf("got %d files, want 1", len(data.Files))
+	}
+	if !strings.Contains(data.Files[0].Content, "too large") {
+		t.Fatalf("patch content should have been replaced with the size-cap placeholder, got: %s", data.Files[0].Content)
+	}
+}
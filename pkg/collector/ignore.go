@@ -0,0 +1,189 @@
+package collector
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// excludesFileRE extracts the value of core.excludesfile from a gitconfig file.
+var excludesFileRE = regexp.MustCompile(`(?m)^\s*excludesfile\s*=\s*(.+)\s*$`)
+
+// gitDir is always excluded from a walk, the same way real git's own walker
+// never considers its metadata directory part of the tree -- regardless of
+// what .gitignore says, since most repos have no reason to mention it.
+const gitDir = ".git"
+
+// ignoreMatcher is a gitignore.Matcher that also exposes the raw
+// gitignore.MatchResult for a path, so callers can tell an explicit
+// negation (Include) apart from simply never having matched anything
+// (NoMatch) -- a distinction gitignore.Matcher's plain bool collapses.
+type ignoreMatcher struct {
+	patterns []gitignore.Pattern
+}
+
+// MatchResult matches path against patterns in priority order (highest
+// priority last, as buildIgnorePatterns assembles them) and returns the
+// first pattern's verdict, exactly like gitignore.Matcher.Match but without
+// discarding whether it was an Include or an Exclude.
+func (m *ignoreMatcher) MatchResult(path []string, isDir bool) gitignore.MatchResult {
+	for i := len(m.patterns) - 1; i >= 0; i-- {
+		if result := m.patterns[i].Match(path, isDir); result > gitignore.NoMatch {
+			return result
+		}
+	}
+	return gitignore.NoMatch
+}
+
+func (m *ignoreMatcher) Match(path []string, isDir bool) bool {
+	return m.MatchResult(path, isDir) == gitignore.Exclude
+}
+
+// ignoreFS wraps an fs.FS and hides any entry matched by an ignoreMatcher,
+// so callers can walk it with fs.WalkDir without special-casing ignore rules.
+type ignoreFS struct {
+	fs.FS
+	matcher *ignoreMatcher
+}
+
+func newIgnoreFS(underlying fs.FS, matcher *ignoreMatcher) fs.FS {
+	return &ignoreFS{FS: underlying, matcher: matcher}
+}
+
+func (i *ignoreFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := fs.ReadDir(i.FS, name)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := entries[:0]
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() == gitDir {
+			continue
+		}
+		entryPath := entry.Name()
+		if name != "." {
+			entryPath = path.Join(name, entry.Name())
+		}
+		if i.matcher.Match(strings.Split(entryPath, "/"), entry.IsDir()) {
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	return kept, nil
+}
+
+// buildIgnorePatterns assembles the full set of gitignore.Pattern values that
+// apply to fsys, in increasing priority order as gitignore.Matcher expects:
+// the global excludesfile first, then every .gitignore found while walking
+// fsys, then the caller's own rules last -- so an explicit IgnorePatterns
+// entry always outranks whatever a repo's own .gitignore says, the same way
+// git lets command-line/config excludes win over tracked ignore files.
+// includeGlobalExcludes is false for in-memory fs.FS values (fstest.MapFS in
+// tests, or any caller-supplied FS unrelated to the real disk), so their
+// behavior doesn't depend on the host's ~/.gitconfig.
+func buildIgnorePatterns(fsys fs.FS, rules []IgnoreRule, includeGlobalExcludes bool) []gitignore.Pattern {
+	var patterns []gitignore.Pattern
+
+	if includeGlobalExcludes {
+		patterns = append(patterns, readGlobalExcludes()...)
+	}
+
+	_ = fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if d.Name() == gitDir {
+			return fs.SkipDir
+		}
+		gitignorePath := p + "/.gitignore"
+		if p == "." {
+			gitignorePath = ".gitignore"
+		}
+		content, err := fs.ReadFile(fsys, gitignorePath)
+		if err != nil {
+			return nil
+		}
+
+		var domain []string
+		if p != "." {
+			domain = strings.Split(p, "/")
+		}
+		for _, line := range strings.Split(string(content), "\n") {
+			line = strings.TrimRight(line, "\r")
+			if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+				continue
+			}
+			patterns = append(patterns, gitignore.ParsePattern(line, domain))
+		}
+		return nil
+	})
+
+	for _, rule := range rules {
+		patterns = append(patterns, gitignore.ParsePattern(rule.Pattern, nil))
+	}
+
+	return patterns
+}
+
+// readGlobalExcludes loads the user's global gitignore file, i.e. whatever
+// core.excludesfile in ~/.gitconfig points at, falling back to the XDG
+// default of ~/.config/git/ignore.
+func readGlobalExcludes() []gitignore.Pattern {
+	excludesPath := globalExcludesFilePath()
+	if excludesPath == "" {
+		return nil
+	}
+
+	content, err := os.ReadFile(excludesPath)
+	if err != nil {
+		return nil
+	}
+
+	var patterns []gitignore.Pattern
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		patterns = append(patterns, gitignore.ParsePattern(line, nil))
+	}
+	return patterns
+}
+
+// globalExcludesFilePath resolves core.excludesfile from ~/.gitconfig,
+// falling back to ~/.config/git/ignore if it exists. Returns "" if neither is
+// configured.
+func globalExcludesFilePath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	if gitconfig, err := os.ReadFile(filepath.Join(home, ".gitconfig")); err == nil {
+		if match := excludesFileRE.FindSubmatch(gitconfig); match != nil {
+			return expandHomePath(strings.TrimSpace(string(match[1])), home)
+		}
+	}
+
+	defaultPath := filepath.Join(home, ".config", "git", "ignore")
+	if _, err := os.Stat(defaultPath); err == nil {
+		return defaultPath
+	}
+	return ""
+}
+
+func expandHomePath(p, home string) string {
+	if p == "~" {
+		return home
+	}
+	if strings.HasPrefix(p, "~/") {
+		return filepath.Join(home, p[2:])
+	}
+	return p
+}
@@ -0,0 +1,25 @@
+package collector
+
+// Config holds the configuration for the code collector.
+type Config struct {
+	IncludeExtensions []string `yaml:"include_extensions"`
+	IgnorePatterns    []string `yaml:"ignore_patterns"`
+}
+
+// FileData represents the collected data for a single file.
+type FileData struct {
+	RelativePath string `json:"relative_path"`
+	Content      string `json:"content"`
+}
+
+// CollectedData represents the overall collected data.
+type CollectedData struct {
+	Tree  string     `json:"tree"`
+	Files []FileData `json:"files"`
+}
+
+// IgnoreRule represents a single ignore rule.
+type IgnoreRule struct {
+	Pattern string
+	Source  string // Where the rule came from, e.g. "user-config".
+}